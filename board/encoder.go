@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"go.viam.com/utils"
 
@@ -20,19 +21,172 @@ type Encoder interface {
 
 	// Start starts a background thread to run the encoder, if there is none needed this is a no-op
 	Start(cancelCtx context.Context, activeBackgroundWorkers *sync.WaitGroup, onStart func())
+
+	// Velocity returns the current speed in ticks per second, computed over
+	// a trailing window of recent samples.
+	Velocity(ctx context.Context) (ticksPerSecond float64, err error)
+
+	// RPM returns the current speed in revolutions per minute, given how
+	// many ticks make up one revolution.
+	RPM(ctx context.Context, ticksPerRev int) (float64, error)
+}
+
+// EncoderOptions configures the optional behaviors of an encoder constructor.
+type EncoderOptions struct {
+	// VelocityWindowSize is how many trailing samples Velocity averages
+	// over. Zero means defaultVelocityWindowSize.
+	VelocityWindowSize int
+}
+
+// IndexOptions configures a SetHome call.
+type IndexOptions struct {
+	// Offset is the position the next index pulse should latch to, instead of zero.
+	Offset int64
+}
+
+// IndexedEncoder is an Encoder that also exposes a hardware index (Z) channel,
+// letting consumers home against a physical reference instead of assuming
+// power-on position is zero.
+type IndexedEncoder interface {
+	Encoder
+
+	// Index returns the position captured at the last rising edge of the
+	// index/Z channel.
+	Index(ctx context.Context) (int64, error)
+
+	// SetHome arms the index latch: at the next index pulse, position is
+	// atomically rewritten so that pulse becomes opts.Offset.
+	SetHome(ctx context.Context, opts IndexOptions) error
 }
 
 // ---------
 
+// quadNoChange and quadError are the non-directional outcomes a quadrature
+// transition can produce; a legal transition instead yields +1 or -1.
+const (
+	quadNoChange = 0
+	quadError    = 2
+)
+
+// quadratureTable maps a transition idx = (prev<<2)|curr, where prev and
+// curr are each a 2-bit (A<<1)|B state, to the position delta it represents.
+// The four "diagonal" transitions (0<->3, 1<->2) skip a state and can only
+// happen if an edge was missed, so they're flagged as quadError instead of
+// guessing a direction.
+var quadratureTable = [16]int8{
+	quadNoChange, 1, -1, quadError, // prev 0: 0->0, 0->1, 0->2, 0->3
+	-1, quadNoChange, quadError, 1, // prev 1: 1->0, 1->1, 1->2, 1->3
+	1, quadError, quadNoChange, -1, // prev 2: 2->0, 2->1, 2->2, 2->3
+	quadError, -1, 1, quadNoChange, // prev 3: 3->0, 3->1, 3->2, 3->3
+}
+
+// defaultVelocityWindowSize is how many trailing samples Velocity averages
+// over when an encoder's EncoderOptions doesn't specify one.
+const defaultVelocityWindowSize = 32
+
+// velocitySample is one (position, time) point fed into a velocityWindow.
+type velocitySample struct {
+	position int64
+	t        time.Time
+}
+
+// velocityWindow tracks the last size samples of an encoder's position and
+// reports the least-squares slope through them, in ticks per second. Samples
+// live in a ring buffer allocated once in newVelocityWindow, so add() never
+// allocates on the interrupt goroutine's hot path; a mutex guards it instead
+// of the copy-on-write scheme a truly lock-free ring would need to stay
+// race-free on a wrapping read.
+type velocityWindow struct {
+	mu      sync.Mutex
+	samples []velocitySample // ring buffer of length size
+	size    int
+	next    int // index the next add() writes to
+	count   int // valid samples so far, saturates at size
+}
+
+func newVelocityWindow(size int) *velocityWindow {
+	if size <= 0 {
+		size = defaultVelocityWindowSize
+	}
+	return &velocityWindow{samples: make([]velocitySample, size), size: size}
+}
+
+func (w *velocityWindow) add(position int64, t time.Time) {
+	w.mu.Lock()
+	w.samples[w.next] = velocitySample{position, t}
+	w.next = (w.next + 1) % w.size
+	if w.count < w.size {
+		w.count++
+	}
+	w.mu.Unlock()
+}
+
+// ticksPerSecond returns the least-squares slope of position against time
+// over the current window, or 0 if there aren't at least two samples yet.
+func (w *velocityWindow) ticksPerSecond() float64 {
+	w.mu.Lock()
+	count := w.count
+	start := w.next
+	if count < w.size {
+		start = 0
+	}
+	samples := make([]velocitySample, count)
+	for i := 0; i < count; i++ {
+		samples[i] = w.samples[(start+i)%w.size]
+	}
+	w.mu.Unlock()
+
+	if count < 2 {
+		return 0
+	}
+
+	t0 := samples[0].t
+	var n, sumX, sumY, sumXY, sumXX float64
+	for _, s := range samples {
+		x := s.t.Sub(t0).Seconds()
+		y := float64(s.position)
+		n++
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denom
+}
+
 // HallEncoder keeps track of a motor position using a rotary hall encoder
 type HallEncoder struct {
-	a, b     DigitalInterrupt
+	a, b DigitalInterrupt
+	// position is in encoder (quadrature) counts: 4 per mechanical detent.
 	position int64
+	// prev is the last (A<<1)|B state seen, used to index quadratureTable.
+	prev        uint32
+	missedTicks int64
+	velocity    *velocityWindow
 }
 
 // NewHallEncoder creates a new HallEncoder
 func NewHallEncoder(a, b DigitalInterrupt) *HallEncoder {
-	return &HallEncoder{a, b, 0}
+	return NewHallEncoderWithOptions(a, b, EncoderOptions{})
+}
+
+// NewHallEncoderWithOptions creates a new HallEncoder with a configurable
+// velocity window size.
+func NewHallEncoderWithOptions(a, b DigitalInterrupt, opts EncoderOptions) *HallEncoder {
+	return &HallEncoder{
+		a: a, b: b,
+		// Start assumes both lines idle high (aLevel, bLevel := true, true,
+		// i.e. state 3) before the first edge arrives, so prev must match
+		// that or the very first transition gets indexed against the wrong
+		// origin state.
+		prev:     3,
+		velocity: newVelocityWindow(opts.VelocityWindowSize),
+	}
 }
 
 // Start starts the HallEncoder background thread
@@ -43,10 +197,10 @@ func (e *HallEncoder) Start(cancelCtx context.Context, activeBackgroundWorkers *
 	  picture from https://github.com/joan2937/pigpio/blob/master/EXAMPLES/C/ROTARY_ENCODER/rotary_encoder.c
 	    1   2     3    4    1    2    3    4     1
 
-	            +---------+         +---------+      0
-	            |         |         |         |
-	  A         |         |         |         |
-	            |         |         |         |
+	          +---------+         +---------+      0
+	          |         |         |         |
+	  A       |         |         |         |
+	          |         |         |         |
 	  +---------+         +---------+         +----- 1
 
 	      +---------+         +---------+            0
@@ -70,9 +224,6 @@ func (e *HallEncoder) Start(cancelCtx context.Context, activeBackgroundWorkers *
 		aLevel := true
 		bLevel := true
 
-		lastWasA := true
-		lastLevel := true
-
 		for {
 
 			select {
@@ -81,93 +232,188 @@ func (e *HallEncoder) Start(cancelCtx context.Context, activeBackgroundWorkers *
 			default:
 			}
 
-			var level bool
-			var isA bool
-
 			select {
 			case <-cancelCtx.Done():
 				return
-			case level = <-chanA:
-				isA = true
-				aLevel = level
-			case level = <-chanB:
-				isA = false
-				bLevel = level
-			}
-
-			if isA == lastWasA && level == lastLevel {
-				// this means we got the exact same message multiple times
-				// this is probably some sort of hardware issue, so we ignore
-				continue
-			}
-			lastWasA = isA
-			lastLevel = level
-
-			if !aLevel && !bLevel { // state 1
-				if lastWasA {
-					e.inc()
-				} else {
-					e.dec()
-				}
-			} else if !aLevel && bLevel { // state 2
-				if lastWasA {
-					e.dec()
-				} else {
-					e.inc()
-				}
-			} else if aLevel && bLevel { // state 3
-				if lastWasA {
-					e.inc()
-				} else {
-					e.dec()
-				}
-			} else if aLevel && !bLevel { // state 4
-				if lastWasA {
-					e.dec()
-				} else {
-					e.inc()
-				}
+			case aLevel = <-chanA:
+			case bLevel = <-chanB:
 			}
 
+			e.processTransition(aLevel, bLevel)
 		}
 	}, activeBackgroundWorkers.Done)
 }
 
-// Position returns the current position
+// processTransition advances the quadrature state machine to the given A/B
+// levels, adjusting position (or missedTicks on an illegal transition).
+func (e *HallEncoder) processTransition(aLevel, bLevel bool) {
+	curr := uint32(0)
+	if aLevel {
+		curr |= 1 << 1
+	}
+	if bLevel {
+		curr |= 1
+	}
+
+	prev := atomic.LoadUint32(&e.prev)
+	idx := (prev << 2) | curr
+	switch delta := quadratureTable[idx]; delta {
+	case quadError:
+		atomic.AddInt64(&e.missedTicks, 1)
+	default:
+		pos := atomic.AddInt64(&e.position, int64(delta))
+		e.velocity.add(pos, time.Now())
+	}
+	atomic.StoreUint32(&e.prev, curr)
+}
+
+// Position returns the current position in mechanical detents (encoder
+// counts divided by 4).
 func (e *HallEncoder) Position(ctx context.Context) (int64, error) {
+	return atomic.LoadInt64(&e.position) / 4, nil
+}
+
+// Ticks returns the current position in raw encoder (quadrature) counts,
+// 4 of which make up one mechanical detent.
+func (e *HallEncoder) Ticks(ctx context.Context) (int64, error) {
 	return atomic.LoadInt64(&e.position), nil
 }
 
 // Zero resets the position to zero/home
 func (e *HallEncoder) Zero(ctx context.Context, offset int64) error {
-	atomic.StoreInt64(&e.position, offset)
+	atomic.StoreInt64(&e.position, offset*4)
 	return nil
 }
 
-// RawPosition returns the raw position of the encoder.
+// RawPosition returns the raw (encoder count) position of the encoder.
 func (e *HallEncoder) RawPosition() int64 {
 	return atomic.LoadInt64(&e.position)
 }
 
-func (e *HallEncoder) inc() {
-	atomic.AddInt64(&e.position, 1)
+// MissedTicks returns the number of illegal quadrature transitions observed,
+// each of which indicates at least one missed interrupt edge.
+func (e *HallEncoder) MissedTicks() int64 {
+	return atomic.LoadInt64(&e.missedTicks)
+}
+
+// Velocity returns the current speed in encoder (quadrature) ticks per
+// second, computed over a trailing window of recent samples.
+func (e *HallEncoder) Velocity(ctx context.Context) (float64, error) {
+	return e.velocity.ticksPerSecond(), nil
 }
 
-func (e *HallEncoder) dec() {
-	atomic.AddInt64(&e.position, -1)
+// RPM returns the current speed in revolutions per minute, given how many
+// encoder ticks make up one revolution.
+func (e *HallEncoder) RPM(ctx context.Context, ticksPerRev int) (float64, error) {
+	return e.velocity.ticksPerSecond() / float64(ticksPerRev) * 60, nil
+}
+
+// ---------
+
+// HallEncoderWithIndex is a HallEncoder that also homes against a third
+// "index" or "Z" digital interrupt, which pulses once per full mechanical
+// rotation.
+type HallEncoderWithIndex struct {
+	*HallEncoder
+	z DigitalInterrupt
+
+	indexPosition int64
+	homeArmed     int32 // 1 while a SetHome call is waiting for the next pulse
+	homeOffset    int64
+}
+
+// NewHallEncoderWithIndex creates a new HallEncoderWithIndex.
+func NewHallEncoderWithIndex(a, b, z DigitalInterrupt) *HallEncoderWithIndex {
+	return &HallEncoderWithIndex{HallEncoder: NewHallEncoder(a, b), z: z}
+}
+
+// NewHallEncoderFromPins builds a HallEncoder from header pin names (e.g.
+// "P8_13") instead of raw DigitalInterrupts, resolving aName/bName against
+// desc's PinMap and interrupts so config files can reference a board's
+// silkscreened pin names rather than GPIO numbers.
+func NewHallEncoderFromPins(desc Descriptor, interrupts map[string]DigitalInterrupt, aName, bName string) (*HallEncoder, error) {
+	a, err := ResolveDigitalInterrupt(desc, interrupts, aName)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ResolveDigitalInterrupt(desc, interrupts, bName)
+	if err != nil {
+		return nil, err
+	}
+	return NewHallEncoder(a, b), nil
+}
+
+// Start starts the A/B quadrature loop along with a goroutine that watches
+// the index channel for rising edges.
+func (e *HallEncoderWithIndex) Start(cancelCtx context.Context, activeBackgroundWorkers *sync.WaitGroup, onStart func()) {
+	e.HallEncoder.Start(cancelCtx, activeBackgroundWorkers, onStart)
+
+	chanZ := make(chan bool)
+	e.z.AddCallback(chanZ)
+
+	activeBackgroundWorkers.Add(1)
+	utils.ManagedGo(func() {
+		for {
+			select {
+			case <-cancelCtx.Done():
+				return
+			default:
+			}
+
+			var level bool
+			select {
+			case <-cancelCtx.Done():
+				return
+			case level = <-chanZ:
+			}
+			if !level {
+				continue
+			}
+
+			e.onIndexPulse()
+		}
+	}, activeBackgroundWorkers.Done)
+}
+
+// onIndexPulse latches the current position and, if a SetHome is pending,
+// rewrites position so this pulse becomes homeOffset. The latch happens
+// after the rewrite, so Index() reports homeOffset (not the pre-home raw
+// position) for the pulse that triggered it.
+func (e *HallEncoderWithIndex) onIndexPulse() {
+	if atomic.CompareAndSwapInt32(&e.homeArmed, 1, 0) {
+		atomic.StoreInt64(&e.position, atomic.LoadInt64(&e.homeOffset)*4)
+	}
+
+	pos := atomic.LoadInt64(&e.position)
+	atomic.StoreInt64(&e.indexPosition, pos)
+}
+
+// Index returns the position captured at the last rising edge of the index
+// channel.
+func (e *HallEncoderWithIndex) Index(ctx context.Context) (int64, error) {
+	return atomic.LoadInt64(&e.indexPosition) / 4, nil
+}
+
+// SetHome arms the index latch: at the next index pulse, position is
+// atomically rewritten so that pulse becomes opts.Offset.
+func (e *HallEncoderWithIndex) SetHome(ctx context.Context, opts IndexOptions) error {
+	atomic.StoreInt64(&e.homeOffset, opts.Offset)
+	atomic.StoreInt32(&e.homeArmed, 1)
+	return nil
 }
 
 // ---------
 
 // NewSingleEncoder creates a new SingleEncoder
 func NewSingleEncoder(i DigitalInterrupt) *SingleEncoder {
-	return &SingleEncoder{i: i}
+	return &SingleEncoder{i: i, velocity: newVelocityWindow(0)}
 }
 
 // SingleEncoder is a single interrupt based encoder.
 type SingleEncoder struct {
 	i        DigitalInterrupt
 	position int64
+	velocity *velocityWindow
 	M        *EncodedMotor // note: this is gross, but not sure anyone should use this, so....
 }
 
@@ -178,7 +424,6 @@ func (e *SingleEncoder) Start(cancelCtx context.Context, activeBackgroundWorkers
 	activeBackgroundWorkers.Add(1)
 	utils.ManagedGo(func() {
 		onStart()
-		_, rpmDebug := getRPMSleepDebug()
 		for {
 			select {
 			case <-cancelCtx.Done():
@@ -194,13 +439,16 @@ func (e *SingleEncoder) Start(cancelCtx context.Context, activeBackgroundWorkers
 
 			dir := e.M.rawDirection()
 			if dir == pb.DirectionRelative_DIRECTION_RELATIVE_FORWARD {
-				atomic.AddInt64(&e.position, 1)
+				pos := atomic.AddInt64(&e.position, 1)
+				e.velocity.add(pos, time.Now())
 				//stop = m.state.regulated && m.state.curPosition >= m.state.setPoint
 			} else if dir == pb.DirectionRelative_DIRECTION_RELATIVE_BACKWARD {
-				atomic.AddInt64(&e.position, -1)
+				pos := atomic.AddInt64(&e.position, -1)
+				e.velocity.add(pos, time.Now())
 				//stop = m.state.regulated && m.state.curPosition <= m.state.setPoint
-			} else if rpmDebug {
-				e.M.logger.Warn("got encoder tick but motor should be off")
+			} else {
+				// Routine during coast-down after a stop, not worth a Warn.
+				e.M.logger.Debug("got encoder tick but motor should be off")
 			}
 		}
 	}, activeBackgroundWorkers.Done)
@@ -215,4 +463,16 @@ func (e *SingleEncoder) Position(ctx context.Context) (int64, error) {
 func (e *SingleEncoder) Zero(ctx context.Context, offset int64) error {
 	atomic.StoreInt64(&e.position, offset)
 	return nil
+}
+
+// Velocity returns the current speed in ticks per second, computed over a
+// trailing window of recent samples.
+func (e *SingleEncoder) Velocity(ctx context.Context) (float64, error) {
+	return e.velocity.ticksPerSecond(), nil
+}
+
+// RPM returns the current speed in revolutions per minute, given how many
+// ticks make up one revolution.
+func (e *SingleEncoder) RPM(ctx context.Context, ticksPerRev int) (float64, error) {
+	return e.velocity.ticksPerSecond() / float64(ticksPerRev) * 60, nil
 }
\ No newline at end of file
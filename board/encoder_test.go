@@ -0,0 +1,155 @@
+package board
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestQuadratureTableLegalTransitions(t *testing.T) {
+	// The legal +1 cycle is 0->1->3->2->0; the reverse is -1.
+	forward := []uint32{0, 1, 3, 2}
+	for i, prev := range forward {
+		curr := forward[(i+1)%len(forward)]
+		idx := (prev << 2) | curr
+		if got := quadratureTable[idx]; got != 1 {
+			t.Errorf("forward %d->%d: got %d, want 1", prev, curr, got)
+		}
+	}
+
+	reverse := []uint32{0, 2, 3, 1}
+	for i, prev := range reverse {
+		curr := reverse[(i+1)%len(reverse)]
+		idx := (prev << 2) | curr
+		if got := quadratureTable[idx]; got != -1 {
+			t.Errorf("reverse %d->%d: got %d, want -1", prev, curr, got)
+		}
+	}
+
+	for _, s := range []uint32{0, 1, 2, 3} {
+		idx := (s << 2) | s
+		if got := quadratureTable[idx]; got != quadNoChange {
+			t.Errorf("idempotent %d->%d: got %d, want %d", s, s, got, quadNoChange)
+		}
+	}
+
+	for _, pair := range [][2]uint32{{0, 3}, {3, 0}, {1, 2}, {2, 1}} {
+		idx := (pair[0] << 2) | pair[1]
+		if got := quadratureTable[idx]; got != quadError {
+			t.Errorf("diagonal %d->%d: got %d, want quadError", pair[0], pair[1], got)
+		}
+	}
+}
+
+func TestHallEncoderStartsAtIdleHighState(t *testing.T) {
+	e := NewHallEncoder(nil, nil)
+	if e.prev != 3 {
+		t.Errorf("prev = %d, want 3 (A=1,B=1, matching Start's initial aLevel/bLevel)", e.prev)
+	}
+}
+
+func TestHallEncoderProcessTransition(t *testing.T) {
+	e := NewHallEncoder(nil, nil)
+
+	// A full +1 quadrature cycle from the idle-high start state: 3->2->0->1->3.
+	// (aLevel, bLevel) encodes state as (aLevel<<1)|bLevel.
+	for _, levels := range [][2]bool{
+		{true, false},  // -> state 2
+		{false, false}, // -> state 0
+		{false, true},  // -> state 1
+		{true, true},   // -> state 3
+	} {
+		e.processTransition(levels[0], levels[1])
+	}
+
+	if pos := e.RawPosition(); pos != 4 {
+		t.Errorf("RawPosition() = %d, want 4", pos)
+	}
+	if ticks, _ := e.Ticks(context.Background()); ticks != 4 {
+		t.Errorf("Ticks() = %d, want 4", ticks)
+	}
+	if mech, _ := e.Position(context.Background()); mech != 1 {
+		t.Errorf("Position() = %d, want 1", mech)
+	}
+	if missed := e.MissedTicks(); missed != 0 {
+		t.Errorf("MissedTicks() = %d, want 0", missed)
+	}
+}
+
+func TestHallEncoderProcessTransitionMissedEdge(t *testing.T) {
+	e := NewHallEncoder(nil, nil)
+
+	// Start state is 3 (A=1,B=1); jumping straight to state 0 (A=0,B=0) skips
+	// a state and can't be resolved to a direction.
+	e.processTransition(false, false)
+
+	if pos := e.RawPosition(); pos != 0 {
+		t.Errorf("RawPosition() = %d, want 0 (position untouched by an illegal transition)", pos)
+	}
+	if missed := e.MissedTicks(); missed != 1 {
+		t.Errorf("MissedTicks() = %d, want 1", missed)
+	}
+}
+
+func TestHallEncoderWithIndexSetHomeLatchesOnNextPulse(t *testing.T) {
+	e := NewHallEncoderWithIndex(nil, nil, nil)
+
+	e.processTransition(true, false) // legal +1 transition off the idle-high start state
+	if err := e.SetHome(context.Background(), IndexOptions{Offset: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	e.onIndexPulse()
+
+	if pos := e.RawPosition(); pos != 20 { // 5 mechanical detents * 4 ticks/detent
+		t.Errorf("RawPosition() after home = %d, want 20", pos)
+	}
+	if idx, _ := e.Index(context.Background()); idx != 5 {
+		t.Errorf("Index() = %d, want 5", idx)
+	}
+
+	// A second pulse with no SetHome pending should only update Index, not
+	// rewrite position again.
+	e.processTransition(false, false)
+	e.onIndexPulse()
+	if pos := e.RawPosition(); pos != 21 {
+		t.Errorf("RawPosition() after second pulse = %d, want 21", pos)
+	}
+}
+
+func TestVelocityWindowLeastSquaresSlope(t *testing.T) {
+	w := newVelocityWindow(0)
+	base := time.Now()
+	for i := int64(0); i < 5; i++ {
+		w.add(i*10, base.Add(time.Duration(i)*time.Second))
+	}
+	// position increases by 10 ticks every second: slope should be ~10.
+	if got := w.ticksPerSecond(); got < 9.99 || got > 10.01 {
+		t.Errorf("ticksPerSecond() = %v, want ~10", got)
+	}
+}
+
+func TestVelocityWindowNeedsTwoSamples(t *testing.T) {
+	w := newVelocityWindow(0)
+	if got := w.ticksPerSecond(); got != 0 {
+		t.Errorf("ticksPerSecond() with no samples = %v, want 0", got)
+	}
+	w.add(0, time.Now())
+	if got := w.ticksPerSecond(); got != 0 {
+		t.Errorf("ticksPerSecond() with one sample = %v, want 0", got)
+	}
+}
+
+func TestVelocityWindowEvictsOldSamples(t *testing.T) {
+	w := newVelocityWindow(3)
+	base := time.Now()
+	// A noisy first sample that should fall out of the window once it grows
+	// past size 3.
+	w.add(1000, base)
+	for i := int64(1); i <= 3; i++ {
+		w.add(i*10, base.Add(time.Duration(i)*time.Second))
+	}
+	if got := w.ticksPerSecond(); got < 9.99 || got > 10.01 {
+		t.Errorf("ticksPerSecond() = %v, want ~10 once the stale sample is evicted", got)
+	}
+}
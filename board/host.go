@@ -0,0 +1,250 @@
+package board
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// Host identifies a family of single-board computers that rdk knows how to
+// drive directly (as opposed to through a remote microcontroller).
+type Host string
+
+// Known hosts. Additional hosts can be added by calling RegisterHost from an
+// implementation's init().
+const (
+	HostPi     Host = "pi"
+	HostBBB    Host = "beaglebone-black"
+	HostJetson Host = "jetson"
+	HostOdroid Host = "odroid"
+)
+
+// PinCapability is a bitmask of what a physical header pin can be used for.
+type PinCapability int
+
+// Capabilities a pin may advertise. A pin can support more than one, e.g. a
+// pin that is both a normal GPIO and a hardware PWM output.
+const (
+	CapabilityNormal PinCapability = 1 << iota
+	CapabilityPWM
+	CapabilityI2C
+	CapabilitySPI
+	CapabilityUART
+)
+
+// Has returns true if c includes the given capability.
+func (c PinCapability) Has(other PinCapability) bool {
+	return c&other == other
+}
+
+// Pin describes a single physical header pin on a board.
+type Pin struct {
+	// Name is the canonical header name, e.g. "P8_13" or "GPIO17".
+	Name string
+	// Aliases are other names the same pin is commonly known by, e.g.
+	// "GPIO_23" or "EHRPWM2B".
+	Aliases []string
+	// GPIO is the number the host's GPIO subsystem (e.g. sysfs or
+	// /dev/gpiochip0) uses to address this pin.
+	GPIO int
+	// Capabilities is the set of peripherals this pin can be muxed to.
+	Capabilities PinCapability
+}
+
+// matches returns true if name refers to this pin, either by its canonical
+// Name or one of its Aliases.
+func (p Pin) matches(name string) bool {
+	if p.Name == name {
+		return true
+	}
+	for _, a := range p.Aliases {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PinMap is the full set of physical header pins exposed by a board.
+type PinMap struct {
+	pins []Pin
+}
+
+// NewPinMap creates a PinMap from the given pins.
+func NewPinMap(pins []Pin) PinMap {
+	return PinMap{pins: pins}
+}
+
+// Lookup finds a pin by its canonical name or any of its aliases.
+func (m PinMap) Lookup(name string) (Pin, bool) {
+	for _, p := range m.pins {
+		if p.matches(name) {
+			return p, true
+		}
+	}
+	return Pin{}, false
+}
+
+// All returns every pin in the map.
+func (m PinMap) All() []Pin {
+	return m.pins
+}
+
+// Descriptor describes the peripherals and pinout of a supported host.
+type Descriptor struct {
+	GPIO bool
+	I2C  bool
+	SPI  bool
+	PWM  bool
+	Pins PinMap
+}
+
+var hostDescriptors = map[Host]func() Descriptor{}
+
+// RegisterHost registers a factory that builds the Descriptor for host h.
+// Board implementations call this from their init() so GetDescriptor can find
+// them without this package importing them directly. DetectHost does not
+// consult this registry; it only recognizes the hosts in modelSignatures.
+func RegisterHost(h Host, f func() Descriptor) {
+	hostDescriptors[h] = f
+}
+
+// GetDescriptor returns the Descriptor registered for h, if any.
+func GetDescriptor(h Host) (Descriptor, bool) {
+	f, ok := hostDescriptors[h]
+	if !ok {
+		return Descriptor{}, false
+	}
+	return f(), true
+}
+
+// deviceTreeModelPath is where DetectHost looks for the board model string.
+// It's a var so tests can point it elsewhere.
+var deviceTreeModelPath = "/proc/device-tree/model"
+
+// modelSignatures maps a substring found in /proc/device-tree/model to the
+// Host it identifies. Checked in order, first match wins.
+var modelSignatures = []struct {
+	substr string
+	host   Host
+}{
+	{"Raspberry Pi", HostPi},
+	{"BeagleBone Black", HostBBB},
+	{"Jetson", HostJetson},
+	{"ODROID", HostOdroid},
+}
+
+// DetectHost inspects /proc/device-tree/model to determine which Host this
+// process is running on.
+func DetectHost() (Host, error) {
+	data, err := ioutil.ReadFile(deviceTreeModelPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", deviceTreeModelPath, err)
+	}
+	model := string(data)
+	for _, sig := range modelSignatures {
+		if strings.Contains(model, sig.substr) {
+			return sig.host, nil
+		}
+	}
+	return "", fmt.Errorf("unrecognized board model %q", strings.TrimSpace(model))
+}
+
+// ResolveDigitalInterrupt looks up name in desc's PinMap (by canonical name
+// or alias) and returns the DigitalInterrupt registered for that pin's
+// canonical name in interrupts, letting callers (e.g. encoder constructors)
+// take a header pin name like "P8_13" instead of a raw GPIO number.
+func ResolveDigitalInterrupt(desc Descriptor, interrupts map[string]DigitalInterrupt, name string) (DigitalInterrupt, error) {
+	pin, ok := desc.Pins.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("no pin named %q in this host's PinMap", name)
+	}
+	di, ok := interrupts[pin.Name]
+	if !ok {
+		return nil, fmt.Errorf("pin %q has no DigitalInterrupt registered", pin.Name)
+	}
+	return di, nil
+}
+
+// init registers the Descriptors for the hosts rdk drives directly. Pin data
+// below covers only a subset of each header; which I2C, SPI, UART and
+// PWM-capable pins are listed varies by host (only the Pi lists SPI pins),
+// and it isn't a transcription of every silkscreened pin on each header.
+func init() {
+	RegisterHost(HostPi, func() Descriptor {
+		return Descriptor{
+			GPIO: true, I2C: true, SPI: true, PWM: true,
+			Pins: NewPinMap([]Pin{
+				{Name: "GPIO2", Aliases: []string{"SDA1"}, GPIO: 2, Capabilities: CapabilityNormal | CapabilityI2C},
+				{Name: "GPIO3", Aliases: []string{"SCL1"}, GPIO: 3, Capabilities: CapabilityNormal | CapabilityI2C},
+				{Name: "GPIO4", GPIO: 4, Capabilities: CapabilityNormal},
+				{Name: "GPIO14", Aliases: []string{"TXD0"}, GPIO: 14, Capabilities: CapabilityNormal | CapabilityUART},
+				{Name: "GPIO15", Aliases: []string{"RXD0"}, GPIO: 15, Capabilities: CapabilityNormal | CapabilityUART},
+				{Name: "GPIO17", GPIO: 17, Capabilities: CapabilityNormal},
+				{Name: "GPIO18", Aliases: []string{"PCM_CLK", "PWM0"}, GPIO: 18, Capabilities: CapabilityNormal | CapabilityPWM},
+				{Name: "GPIO27", GPIO: 27, Capabilities: CapabilityNormal},
+				{Name: "GPIO22", GPIO: 22, Capabilities: CapabilityNormal},
+				{Name: "GPIO23", GPIO: 23, Capabilities: CapabilityNormal},
+				{Name: "GPIO24", GPIO: 24, Capabilities: CapabilityNormal},
+				{Name: "GPIO10", Aliases: []string{"MOSI"}, GPIO: 10, Capabilities: CapabilityNormal | CapabilitySPI},
+				{Name: "GPIO9", Aliases: []string{"MISO"}, GPIO: 9, Capabilities: CapabilityNormal | CapabilitySPI},
+				{Name: "GPIO11", Aliases: []string{"SCLK"}, GPIO: 11, Capabilities: CapabilityNormal | CapabilitySPI},
+				{Name: "GPIO8", Aliases: []string{"CE0"}, GPIO: 8, Capabilities: CapabilityNormal | CapabilitySPI},
+				{Name: "GPIO7", Aliases: []string{"CE1"}, GPIO: 7, Capabilities: CapabilityNormal | CapabilitySPI},
+				{Name: "GPIO25", GPIO: 25, Capabilities: CapabilityNormal},
+				{Name: "GPIO12", Aliases: []string{"PWM0_ALT"}, GPIO: 12, Capabilities: CapabilityNormal | CapabilityPWM},
+				{Name: "GPIO13", Aliases: []string{"PWM1"}, GPIO: 13, Capabilities: CapabilityNormal | CapabilityPWM},
+				{Name: "GPIO19", Aliases: []string{"PCM_FS", "PWM1_ALT"}, GPIO: 19, Capabilities: CapabilityNormal | CapabilityPWM},
+				{Name: "GPIO16", GPIO: 16, Capabilities: CapabilityNormal},
+				{Name: "GPIO20", GPIO: 20, Capabilities: CapabilityNormal},
+				{Name: "GPIO21", GPIO: 21, Capabilities: CapabilityNormal},
+			}),
+		}
+	})
+
+	RegisterHost(HostBBB, func() Descriptor {
+		return Descriptor{
+			GPIO: true, I2C: true, SPI: true, PWM: true,
+			Pins: NewPinMap([]Pin{
+				{Name: "P9_17", Aliases: []string{"GPIO_5", "I2C1_SCL"}, GPIO: 5, Capabilities: CapabilityNormal | CapabilityI2C},
+				{Name: "P9_18", Aliases: []string{"GPIO_4", "I2C1_SDA"}, GPIO: 4, Capabilities: CapabilityNormal | CapabilityI2C},
+				{Name: "P9_19", Aliases: []string{"GPIO_13", "I2C2_SCL"}, GPIO: 13, Capabilities: CapabilityNormal | CapabilityI2C},
+				{Name: "P9_20", Aliases: []string{"GPIO_12", "I2C2_SDA"}, GPIO: 12, Capabilities: CapabilityNormal | CapabilityI2C},
+				{Name: "P9_21", Aliases: []string{"GPIO_3", "UART2_TXD"}, GPIO: 3, Capabilities: CapabilityNormal | CapabilityUART},
+				{Name: "P9_22", Aliases: []string{"GPIO_2", "UART2_RXD"}, GPIO: 2, Capabilities: CapabilityNormal | CapabilityUART},
+				{Name: "P9_24", Aliases: []string{"GPIO_15", "UART1_TXD"}, GPIO: 15, Capabilities: CapabilityNormal | CapabilityUART},
+				{Name: "P9_26", Aliases: []string{"GPIO_14", "UART1_RXD"}, GPIO: 14, Capabilities: CapabilityNormal | CapabilityUART},
+				{Name: "P9_11", Aliases: []string{"GPIO_30"}, GPIO: 30, Capabilities: CapabilityNormal},
+				{Name: "P9_13", Aliases: []string{"GPIO_31"}, GPIO: 31, Capabilities: CapabilityNormal},
+				{Name: "P8_13", Aliases: []string{"GPIO_23", "EHRPWM2B"}, GPIO: 23, Capabilities: CapabilityNormal | CapabilityPWM},
+				{Name: "P8_19", Aliases: []string{"GPIO_22", "EHRPWM2A"}, GPIO: 22, Capabilities: CapabilityNormal | CapabilityPWM},
+				{Name: "P9_42", Aliases: []string{"GPIO_7"}, GPIO: 7, Capabilities: CapabilityNormal},
+			}),
+		}
+	})
+
+	RegisterHost(HostJetson, func() Descriptor {
+		return Descriptor{
+			GPIO: true, I2C: true, SPI: true, PWM: true,
+			Pins: NewPinMap([]Pin{
+				{Name: "GPIO_PE6", Aliases: []string{"PIN32", "PWM0"}, GPIO: 32, Capabilities: CapabilityNormal | CapabilityPWM},
+				{Name: "GPIO_PE7", Aliases: []string{"PIN33", "PWM2"}, GPIO: 33, Capabilities: CapabilityNormal | CapabilityPWM},
+				{Name: "GEN1_I2C_SDA", Aliases: []string{"PIN3"}, GPIO: -1, Capabilities: CapabilityI2C},
+				{Name: "GEN1_I2C_SCL", Aliases: []string{"PIN5"}, GPIO: -1, Capabilities: CapabilityI2C},
+				{Name: "UART1_TXD", Aliases: []string{"PIN8"}, GPIO: -1, Capabilities: CapabilityUART},
+				{Name: "UART1_RXD", Aliases: []string{"PIN10"}, GPIO: -1, Capabilities: CapabilityUART},
+			}),
+		}
+	})
+
+	RegisterHost(HostOdroid, func() Descriptor {
+		return Descriptor{
+			GPIO: true, I2C: true, SPI: true, PWM: true,
+			Pins: NewPinMap([]Pin{
+				{Name: "GPIOX.3", Aliases: []string{"PIN3", "I2C_SDA"}, GPIO: -1, Capabilities: CapabilityI2C},
+				{Name: "GPIOX.2", Aliases: []string{"PIN5", "I2C_SCL"}, GPIO: -1, Capabilities: CapabilityI2C},
+				{Name: "PWM_1", Aliases: []string{"PIN33"}, GPIO: -1, Capabilities: CapabilityPWM},
+			}),
+		}
+	})
+}
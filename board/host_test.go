@@ -0,0 +1,159 @@
+package board
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func withDeviceTreeModel(t *testing.T, contents string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "model")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := deviceTreeModelPath
+	deviceTreeModelPath = path
+	t.Cleanup(func() { deviceTreeModelPath = old })
+}
+
+func TestDetectHost(t *testing.T) {
+	cases := []struct {
+		model string
+		want  Host
+	}{
+		{"Raspberry Pi 4 Model B Rev 1.4\x00", HostPi},
+		{"TI AM335x BeagleBone Black\x00", HostBBB},
+		{"NVIDIA Jetson Nano Developer Kit\x00", HostJetson},
+		{"Hardkernel ODROID-C4\x00", HostOdroid},
+	}
+	for _, c := range cases {
+		withDeviceTreeModel(t, c.model)
+		got, err := DetectHost()
+		if err != nil {
+			t.Errorf("DetectHost() for %q: %v", c.model, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("DetectHost() for %q = %q, want %q", c.model, got, c.want)
+		}
+	}
+}
+
+func TestDetectHostMatchOrder(t *testing.T) {
+	// modelSignatures is checked in order; a string matching more than one
+	// signature should resolve to whichever is listed first.
+	withDeviceTreeModel(t, "Raspberry Pi running a Jetson emulator\x00")
+	got, err := DetectHost()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != HostPi {
+		t.Errorf("DetectHost() = %q, want %q (first match in modelSignatures)", got, HostPi)
+	}
+}
+
+func TestDetectHostUnrecognizedModel(t *testing.T) {
+	withDeviceTreeModel(t, "Some Unknown Board\x00")
+	if _, err := DetectHost(); err == nil {
+		t.Error("expected an error for an unrecognized model")
+	}
+}
+
+func TestDetectHostReadError(t *testing.T) {
+	old := deviceTreeModelPath
+	deviceTreeModelPath = filepath.Join(t.TempDir(), "does-not-exist")
+	t.Cleanup(func() { deviceTreeModelPath = old })
+
+	if _, err := DetectHost(); err == nil {
+		t.Error("expected an error when the model file can't be read")
+	}
+}
+
+func TestGetDescriptorRegistersKnownHosts(t *testing.T) {
+	for _, h := range []Host{HostPi, HostBBB, HostJetson, HostOdroid} {
+		desc, ok := GetDescriptor(h)
+		if !ok {
+			t.Errorf("GetDescriptor(%q) ok = false, want a registered Descriptor", h)
+			continue
+		}
+		if len(desc.Pins.All()) == 0 {
+			t.Errorf("GetDescriptor(%q) has an empty PinMap", h)
+		}
+	}
+
+	if _, ok := GetDescriptor(Host("not-a-real-host")); ok {
+		t.Error("GetDescriptor for an unregistered host returned ok = true")
+	}
+}
+
+func TestPinMapLookupByAlias(t *testing.T) {
+	desc, ok := GetDescriptor(HostBBB)
+	if !ok {
+		t.Fatal("HostBBB not registered")
+	}
+
+	pin, ok := desc.Pins.Lookup("I2C1_SDA")
+	if !ok {
+		t.Fatal("Lookup(\"I2C1_SDA\") ok = false")
+	}
+	if pin.Name != "P9_18" {
+		t.Errorf("Lookup(\"I2C1_SDA\").Name = %q, want \"P9_18\"", pin.Name)
+	}
+	if !pin.Capabilities.Has(CapabilityI2C) {
+		t.Error("P9_18 should have CapabilityI2C")
+	}
+}
+
+type fakeInterrupt struct{}
+
+func (fakeInterrupt) AddCallback(chan bool) {}
+
+func TestResolveDigitalInterrupt(t *testing.T) {
+	desc, ok := GetDescriptor(HostBBB)
+	if !ok {
+		t.Fatal("HostBBB not registered")
+	}
+
+	want := fakeInterrupt{}
+	interrupts := map[string]DigitalInterrupt{"P9_17": want}
+
+	got, err := ResolveDigitalInterrupt(desc, interrupts, "I2C1_SCL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != DigitalInterrupt(want) {
+		t.Errorf("ResolveDigitalInterrupt returned %v, want %v", got, want)
+	}
+
+	if _, err := ResolveDigitalInterrupt(desc, interrupts, "NOT_A_PIN"); err == nil {
+		t.Error("expected an error for a pin not in the PinMap")
+	}
+
+	if _, err := ResolveDigitalInterrupt(desc, interrupts, "P9_18"); err == nil {
+		t.Error("expected an error for a pin with no registered DigitalInterrupt")
+	}
+}
+
+func TestNewHallEncoderFromPins(t *testing.T) {
+	desc, ok := GetDescriptor(HostBBB)
+	if !ok {
+		t.Fatal("HostBBB not registered")
+	}
+	interrupts := map[string]DigitalInterrupt{
+		"P9_17": fakeInterrupt{},
+		"P9_18": fakeInterrupt{},
+	}
+
+	e, err := NewHallEncoderFromPins(desc, interrupts, "I2C1_SCL", "I2C1_SDA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.prev != 3 {
+		t.Errorf("prev = %d, want 3", e.prev)
+	}
+
+	if _, err := NewHallEncoderFromPins(desc, interrupts, "I2C1_SCL", "NOT_A_PIN"); err == nil {
+		t.Error("expected an error when the b pin can't be resolved")
+	}
+}
@@ -0,0 +1,90 @@
+package board
+
+// I2C represents an I2C bus that can be used to send commands to various I2C
+// devices.
+type I2C interface {
+	// OpenHandle locks returns a handle that can be used until it is closed.
+	OpenHandle() (I2CHandle, error)
+}
+
+// I2CHandle is used to exclusively talk to a device via the bus it's on.
+type I2CHandle interface {
+	// Transaction performs ops in order as a single I2C_RDWR ioctl (or the
+	// equivalent repeated-start sequence on non-Linux backends), so a
+	// write-register-then-read without an intervening STOP is seen by the
+	// device as one transaction. It returns the bytes read by each op that
+	// had I2COpRead set, in order; writes get a nil entry.
+	Transaction(ops []I2COp) ([][]byte, error)
+
+	// Close releases access to the bus.
+	Close() error
+}
+
+// I2COpFlag modifies how an I2COp is carried out.
+type I2COpFlag int
+
+const (
+	// I2COpRead marks an op as a read of len(Buf) bytes rather than a write
+	// of Buf.
+	I2COpRead I2COpFlag = 1 << iota
+	// I2COpTenBit marks Addr as a 10-bit address rather than the usual 7-bit one.
+	I2COpTenBit
+	// I2COpNoStart suppresses the repeated START that would otherwise
+	// precede this op, continuing the previous op's transfer.
+	I2COpNoStart
+)
+
+// I2COp is a single write or read, carried out against Addr as part of a
+// Transaction.
+type I2COp struct {
+	Addr  uint16
+	Flags I2COpFlag
+	Buf   []byte
+}
+
+// I2CDevice is a convenience wrapper binding an I2C bus to a single device
+// address, for drivers that only ever talk to one device on the bus.
+type I2CDevice struct {
+	Bus  I2C
+	Addr uint16
+}
+
+// ReadReg writes reg, then reads back n bytes, as a single Transaction with
+// no STOP between the write and the read. This is required by sensors
+// (BMP280, MPU6050, VL53L0X, etc.) that don't tolerate a STOP between
+// selecting a register and reading it. The underlying bus handle is opened
+// and closed around the call, so concurrent callers can't interleave their
+// register transactions.
+func (d I2CDevice) ReadReg(reg byte, n int) ([]byte, error) {
+	handle, err := d.Bus.OpenHandle()
+	if err != nil {
+		return nil, err
+	}
+	defer handle.Close()
+
+	ops := []I2COp{
+		{Addr: d.Addr, Buf: []byte{reg}},
+		{Addr: d.Addr, Flags: I2COpRead, Buf: make([]byte, n)},
+	}
+	results, err := handle.Transaction(ops)
+	if err != nil {
+		return nil, err
+	}
+	return results[1], nil
+}
+
+// WriteReg writes data to reg as a single Transaction, under the same
+// exclusive handle as ReadReg.
+func (d I2CDevice) WriteReg(reg byte, data []byte) error {
+	handle, err := d.Bus.OpenHandle()
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+
+	buf := make([]byte, 0, len(data)+1)
+	buf = append(buf, reg)
+	buf = append(buf, data...)
+	_, err = handle.Transaction([]I2COp{{Addr: d.Addr, Buf: buf}})
+	return err
+}
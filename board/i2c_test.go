@@ -0,0 +1,68 @@
+package board_test
+
+import (
+	"reflect"
+	"testing"
+
+	"go.viam.com/core/board"
+	"go.viam.com/core/testutils/inject"
+)
+
+func TestI2CDeviceReadReg(t *testing.T) {
+	var gotOps []board.I2COp
+	handle := &inject.I2CHandle{
+		TransactionFunc: func(ops []board.I2COp) ([][]byte, error) {
+			gotOps = ops
+			return [][]byte{nil, {0xAB, 0xCD}}, nil
+		},
+		CloseFunc: func() error { return nil },
+	}
+	bus := &inject.I2C{
+		OpenHandleFunc: func() (board.I2CHandle, error) {
+			return handle, nil
+		},
+	}
+	dev := board.I2CDevice{Bus: bus, Addr: 0x76}
+
+	got, err := dev.ReadReg(0xF4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []byte{0xAB, 0xCD}) {
+		t.Errorf("got %v", got)
+	}
+
+	want := []board.I2COp{
+		{Addr: 0x76, Buf: []byte{0xF4}},
+		{Addr: 0x76, Flags: board.I2COpRead, Buf: []byte{0, 0}},
+	}
+	if !reflect.DeepEqual(gotOps, want) {
+		t.Errorf("got ops %+v, want %+v", gotOps, want)
+	}
+}
+
+func TestI2CDeviceWriteReg(t *testing.T) {
+	var gotOps []board.I2COp
+	handle := &inject.I2CHandle{
+		TransactionFunc: func(ops []board.I2COp) ([][]byte, error) {
+			gotOps = ops
+			return [][]byte{nil}, nil
+		},
+		CloseFunc: func() error { return nil },
+	}
+	bus := &inject.I2C{
+		OpenHandleFunc: func() (board.I2CHandle, error) {
+			return handle, nil
+		},
+	}
+	dev := board.I2CDevice{Bus: bus, Addr: 0x68}
+
+	if err := dev.WriteReg(0x6B, []byte{0x00}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []board.I2COp{{Addr: 0x68, Buf: []byte{0x6B, 0x00}}}
+	if !reflect.DeepEqual(gotOps, want) {
+		t.Errorf("got ops %+v, want %+v", gotOps, want)
+	}
+}
@@ -16,4 +16,4 @@ func (s *I2C) OpenHandle() (board.I2CHandle, error) {
 		return s.I2C.OpenHandle()
 	}
 	return s.OpenHandleFunc()
-}
\ No newline at end of file
+}
@@ -0,0 +1,28 @@
+package inject
+
+import (
+	"go.viam.com/core/board"
+)
+
+// I2CHandle is an injected I2CHandle.
+type I2CHandle struct {
+	board.I2CHandle
+	TransactionFunc func(ops []board.I2COp) ([][]byte, error)
+	CloseFunc       func() error
+}
+
+// Transaction calls the injected Transaction or the real version.
+func (h *I2CHandle) Transaction(ops []board.I2COp) ([][]byte, error) {
+	if h.TransactionFunc == nil {
+		return h.I2CHandle.Transaction(ops)
+	}
+	return h.TransactionFunc(ops)
+}
+
+// Close calls the injected Close or the real version.
+func (h *I2CHandle) Close() error {
+	if h.CloseFunc == nil {
+		return h.I2CHandle.Close()
+	}
+	return h.CloseFunc()
+}